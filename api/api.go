@@ -0,0 +1,158 @@
+// Package api 提供一个基于 Gin 的管理/指标接口，
+// 让 ChannelMonitor 从单纯的定时任务变成可按需操作的服务。
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChannelSummary 是 /channels 接口返回的渠道概览
+type ChannelSummary struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Models []string `json:"models"`
+	Status int      `json:"status"`
+}
+
+// HistoryEntry 是单次探测结果记录，具体字段由 chunk0-3 的历史表填充
+type HistoryEntry struct {
+	TestedAt   string `json:"tested_at"`
+	Model      string `json:"model"`
+	OK         bool   `json:"ok"`
+	StatusCode int    `json:"status_code"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Deps 是 api 包对外部状态的全部依赖，由 main 包注入，避免直接依赖 main 包的全局变量
+type Deps struct {
+	AdminToken string
+
+	// TestChannel 立即触发一次指定渠道的探测，每个模型测完就调用一次 onResult，
+	// 全部测完后返回探测到的可用模型列表
+	TestChannel func(ctx context.Context, channelID int, onResult func(model string, ok bool)) ([]string, error)
+	// ListChannels 返回所有渠道及其最近一次已知的可用模型
+	ListChannels func() ([]ChannelSummary, error)
+	// GetHistory 返回某个渠道最近的探测记录
+	GetHistory func(channelID int, limit int) ([]HistoryEntry, error)
+	// SetExcluded 在运行时切换某个渠道是否被排除，无需修改配置文件
+	SetExcluded func(channelID int, excluded bool) error
+}
+
+// NewRouter 构建挂载了全部管理/指标路由的 gin.Engine
+func NewRouter(deps Deps) *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	r.GET("/metrics", func(c *gin.Context) {
+		c.String(http.StatusOK, renderPrometheus())
+	})
+
+	authorized := r.Group("/", authMiddleware(deps.AdminToken))
+	{
+		authorized.GET("/channels", func(c *gin.Context) {
+			channels, err := deps.ListChannels()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, channels)
+		})
+
+		authorized.GET("/channels/:id/history", func(c *gin.Context) {
+			id, err := strconv.Atoi(c.Param("id"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "非法的渠道ID"})
+				return
+			}
+			limit := 50
+			if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+				limit = l
+			}
+			history, err := deps.GetHistory(id, limit)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, history)
+		})
+
+		// 渠道模型数量较多时逐模型探测可能耗时较长，这里按 NDJSON（每行一个 JSON 对象）
+		// 分块流式返回，每测完一个模型就下发一行，而不是等全部测完再一次性响应
+		authorized.POST("/channels/:id/test", func(c *gin.Context) {
+			id, err := strconv.Atoi(c.Param("id"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "非法的渠道ID"})
+				return
+			}
+
+			c.Writer.Header().Set("Content-Type", "application/x-ndjson")
+			c.Writer.WriteHeader(http.StatusOK)
+			flusher, canFlush := c.Writer.(http.Flusher)
+			encoder := json.NewEncoder(c.Writer)
+
+			writeLine := func(v interface{}) {
+				_ = encoder.Encode(v)
+				if canFlush {
+					flusher.Flush()
+				}
+			}
+
+			models, err := deps.TestChannel(c.Request.Context(), id, func(model string, ok bool) {
+				writeLine(gin.H{"model": model, "ok": ok})
+			})
+			if err != nil {
+				writeLine(gin.H{"error": err.Error()})
+				return
+			}
+			writeLine(gin.H{"channel_id": id, "models": models})
+		})
+
+		authorized.POST("/channels/:id/exclude", func(c *gin.Context) {
+			id, err := strconv.Atoi(c.Param("id"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "非法的渠道ID"})
+				return
+			}
+			var body struct {
+				Excluded bool `json:"excluded"`
+			}
+			if err := c.ShouldBindJSON(&body); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err := deps.SetExcluded(id, body.Excluded); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"channel_id": id, "excluded": body.Excluded})
+		})
+	}
+
+	return r
+}
+
+// Serve 启动管理/指标接口，阻塞直至出错
+func Serve(addr string, deps Deps) error {
+	return NewRouter(deps).Run(addr)
+}
+
+func authMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+		auth := c.GetHeader("Authorization")
+		if auth != "Bearer "+token {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+			return
+		}
+		c.Next()
+	}
+}
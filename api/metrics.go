@@ -0,0 +1,113 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metricKey 唯一标识一个 (渠道, 模型) 维度
+type metricKey struct {
+	channelID   int
+	channelName string
+	model       string
+}
+
+var latencyBuckets = []float64{0.1, 0.5, 1, 5} // 单位：秒
+
+type metricsStore struct {
+	mu         sync.Mutex
+	probes     map[metricKey]int64
+	failures   map[metricKey]int64
+	latencySum map[metricKey]float64
+	latencyCnt map[metricKey]int64
+	// bucketCounts[key][i] 表示延迟落在 <= latencyBuckets[i] 的累计请求数
+	bucketCounts map[metricKey][]int64
+}
+
+var metrics = &metricsStore{
+	probes:       make(map[metricKey]int64),
+	failures:     make(map[metricKey]int64),
+	latencySum:   make(map[metricKey]float64),
+	latencyCnt:   make(map[metricKey]int64),
+	bucketCounts: make(map[metricKey][]int64),
+}
+
+// RecordProbe 记录一次探测结果，供 /metrics 接口按渠道/模型维度统计
+func RecordProbe(channelID int, channelName, model string, ok bool, latency time.Duration) {
+	key := metricKey{channelID: channelID, channelName: channelName, model: model}
+	seconds := latency.Seconds()
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.probes[key]++
+	if !ok {
+		metrics.failures[key]++
+	}
+	metrics.latencySum[key] += seconds
+	metrics.latencyCnt[key]++
+
+	buckets, ok2 := metrics.bucketCounts[key]
+	if !ok2 {
+		buckets = make([]int64, len(latencyBuckets))
+		metrics.bucketCounts[key] = buckets
+	}
+	for i, le := range latencyBuckets {
+		if seconds <= le {
+			buckets[i]++
+		}
+	}
+}
+
+// renderPrometheus 将当前指标以 Prometheus 文本暴露格式渲染出来
+func renderPrometheus() string {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	keys := make([]metricKey, 0, len(metrics.probes))
+	for k := range metrics.probes {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].channelID != keys[j].channelID {
+			return keys[i].channelID < keys[j].channelID
+		}
+		return keys[i].model < keys[j].model
+	})
+
+	var sb strings.Builder
+	sb.WriteString("# HELP channelmonitor_probe_total 探测请求总数\n")
+	sb.WriteString("# TYPE channelmonitor_probe_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "channelmonitor_probe_total{channel_id=\"%d\",channel=\"%s\",model=\"%s\"} %d\n",
+			k.channelID, k.channelName, k.model, metrics.probes[k])
+	}
+
+	sb.WriteString("# HELP channelmonitor_probe_failures_total 探测失败总数\n")
+	sb.WriteString("# TYPE channelmonitor_probe_failures_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "channelmonitor_probe_failures_total{channel_id=\"%d\",channel=\"%s\",model=\"%s\"} %d\n",
+			k.channelID, k.channelName, k.model, metrics.failures[k])
+	}
+
+	sb.WriteString("# HELP channelmonitor_probe_latency_seconds 探测请求延迟\n")
+	sb.WriteString("# TYPE channelmonitor_probe_latency_seconds histogram\n")
+	for _, k := range keys {
+		buckets := metrics.bucketCounts[k]
+		for i, le := range latencyBuckets {
+			fmt.Fprintf(&sb, "channelmonitor_probe_latency_seconds_bucket{channel_id=\"%d\",channel=\"%s\",model=\"%s\",le=\"%g\"} %d\n",
+				k.channelID, k.channelName, k.model, le, buckets[i])
+		}
+		fmt.Fprintf(&sb, "channelmonitor_probe_latency_seconds_bucket{channel_id=\"%d\",channel=\"%s\",model=\"%s\",le=\"+Inf\"} %d\n",
+			k.channelID, k.channelName, k.model, metrics.latencyCnt[k])
+		fmt.Fprintf(&sb, "channelmonitor_probe_latency_seconds_sum{channel_id=\"%d\",channel=\"%s\",model=\"%s\"} %g\n",
+			k.channelID, k.channelName, k.model, metrics.latencySum[k])
+		fmt.Fprintf(&sb, "channelmonitor_probe_latency_seconds_count{channel_id=\"%d\",channel=\"%s\",model=\"%s\"} %d\n",
+			k.channelID, k.channelName, k.model, metrics.latencyCnt[k])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/DullJZ/ChannelMonitor/api"
+)
+
+// startAPIServer 在配置了 ApiAddr 时启动管理/指标接口，注入当前进程的依赖。
+// AdminToken 为空意味着管理接口完全不鉴权，这里直接拒绝启动，而不是悄悄裸奔。
+func startAPIServer(cfg *Config) {
+	if cfg.ApiAddr == "" {
+		return
+	}
+	if cfg.AdminToken == "" {
+		log.Fatal("配置了 ApiAddr 但 AdminToken 为空，拒绝以不鉴权状态启动管理接口")
+	}
+
+	deps := api.Deps{
+		AdminToken:   cfg.AdminToken,
+		TestChannel:  testChannelNow,
+		ListChannels: listChannelsForAPI,
+		GetHistory:   getHistoryForAPI,
+		SetExcluded:  setExcluded,
+	}
+
+	go func() {
+		if err := api.Serve(cfg.ApiAddr, deps); err != nil {
+			log.Printf("\033[31m管理接口退出：%v\033[0m\n", err)
+		}
+	}()
+	log.Printf("管理/指标接口已启动，监听地址：%s\n", cfg.ApiAddr)
+}
+
+// listChannelsForAPI 返回所有渠道及其最近一次已知的可用模型，供 GET /channels 使用
+func listChannelsForAPI() ([]api.ChannelSummary, error) {
+	rows, err := db.Query("SELECT id, name, status, models FROM channels")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []api.ChannelSummary
+	for rows.Next() {
+		var id, status int
+		var name, models string
+		if err := rows.Scan(&id, &name, &status, &models); err != nil {
+			return nil, err
+		}
+		var modelList []string
+		if models != "" {
+			modelList = strings.Split(models, ",")
+		}
+		summaries = append(summaries, api.ChannelSummary{ID: id, Name: name, Status: status, Models: modelList})
+	}
+	return summaries, nil
+}
+
+// testChannelNow 立即对单个渠道做一次同步探测，供 POST /channels/:id/test 使用。
+// 每测完一个模型就调用一次 onResult，供调用方在全部测完之前就流式下发结果。
+func testChannelNow(ctx context.Context, channelID int, onResult func(model string, ok bool)) ([]string, error) {
+	row := db.QueryRow("SELECT id, name, base_url, `key`, status FROM channels WHERE id = ?", channelID)
+	var channel Channel
+	if err := row.Scan(&channel.ID, &channel.Name, &channel.BaseURL, &channel.Key, &channel.Status); err != nil {
+		return nil, fmt.Errorf("查询渠道失败：%v", err)
+	}
+	channel.Type = inferProviderType(channel.BaseURL)
+
+	models, err := listModels(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	var available []string
+	for _, model := range models {
+		ok, err := probeModel(ctx, channel, model)
+		if err != nil {
+			log.Printf("\033[31m渠道 %s(ID:%d) 的模型 %s 测试失败：%v\033[0m\n", channel.Name, channel.ID, model, err)
+			onResult(model, false)
+			continue
+		}
+		onResult(model, ok)
+		if ok {
+			available = append(available, model)
+		}
+	}
+
+	if err := updateModels(channel.ID, available); err != nil {
+		return nil, fmt.Errorf("更新渠道模型失败：%v", err)
+	}
+	return available, nil
+}
+
+// getHistoryForAPI 返回某个渠道最近的探测记录，供 GET /channels/:id/history 使用
+func getHistoryForAPI(channelID int, limit int) ([]api.HistoryEntry, error) {
+	rows, err := db.Query(
+		"SELECT tested_at, model, ok, status_code, latency_ms, error FROM channel_model_results WHERE channel_id = ? ORDER BY tested_at DESC LIMIT ?",
+		channelID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []api.HistoryEntry
+	for rows.Next() {
+		var entry api.HistoryEntry
+		var testedAt time.Time
+		var errMsg sql.NullString
+		if err := rows.Scan(&testedAt, &entry.Model, &entry.OK, &entry.StatusCode, &entry.LatencyMs, &errMsg); err != nil {
+			return nil, err
+		}
+		entry.TestedAt = testedAt.Format(time.RFC3339)
+		entry.Error = errMsg.String
+		history = append(history, entry)
+	}
+	return history, nil
+}
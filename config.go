@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/DullJZ/ChannelMonitor/notifier"
+)
+
+type Config struct {
+	DbDsn          string   `json:"db_dsn"`
+	TimePeriod     string   `json:"time_period"`
+	ExcludeChannel []int    `json:"exclude_channel"`
+	Models         []string `json:"models"`
+
+	// Concurrency 为全局探测并发度，PerChannelConcurrency 为单个渠道下同时进行的模型探测数
+	Concurrency           int `json:"concurrency"`
+	PerChannelConcurrency int `json:"per_channel_concurrency"`
+
+	// 每个渠道的限流配置（请求数/秒，突发上限）
+	ChannelRPS   float64 `json:"channel_rps"`
+	ChannelBurst int     `json:"channel_burst"`
+
+	// 全局限流配置，用于控制所有渠道加起来的总请求速率
+	GlobalRPS   float64 `json:"global_rps"`
+	GlobalBurst int     `json:"global_burst"`
+
+	// ApiAddr 为管理/指标接口的监听地址，留空则不启动该接口
+	ApiAddr    string `json:"api_addr"`
+	AdminToken string `json:"admin_token"`
+
+	// ResultWindow 为计算渠道成功率时回看的探测记录条数
+	ResultWindow int `json:"result_window"`
+	// DisableThreshold 为成功率低于该值时视为异常（0~1）
+	DisableThreshold float64 `json:"disable_threshold"`
+	// DisableConsecutiveCycles 为连续多少个周期成功率低于阈值后自动禁用渠道
+	DisableConsecutiveCycles int `json:"disable_consecutive_cycles"`
+	// ReEnableStreak 为自动禁用的渠道连续多少个周期成功率不低于 DisableThreshold 后自动重新启用
+	ReEnableStreak int `json:"re_enable_streak"`
+
+	// Redis* 留空时不启用 Redis 集成，多实例部署时用于渠道锁与结果缓存
+	RedisAddr     string `json:"redis_addr"`
+	RedisPassword string `json:"redis_password"`
+	RedisDB       int    `json:"redis_db"`
+	// CacheTTL 为模型列表/探测结果缓存的有效期，如 "2m"
+	CacheTTL string `json:"cache_ttl"`
+	// ChannelLockTTL 为渠道分布式锁的持有时长，应略长于该渠道一次完整探测预计耗时
+	ChannelLockTTL string `json:"channel_lock_ttl"`
+
+	// ModelFilters 按渠道类型（openai/anthropic/gemini/ollama/embeddings）配置模型名过滤正则
+	ModelFilters map[string]string `json:"model_filters"`
+
+	// Notifiers 配置渠道失联/模型增减时要通知的告警渠道
+	Notifiers []notifier.Config `json:"notifiers"`
+}
+
+func loadConfig() (*Config, error) {
+	file, err := os.Open("config.json")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var config Config
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+
+	if config.Concurrency <= 0 {
+		config.Concurrency = 10
+	}
+	if config.PerChannelConcurrency <= 0 {
+		config.PerChannelConcurrency = 3
+	}
+	if config.ChannelRPS <= 0 {
+		config.ChannelRPS = 2
+	}
+	if config.ChannelBurst <= 0 {
+		config.ChannelBurst = 2
+	}
+	if config.GlobalRPS <= 0 {
+		config.GlobalRPS = 20
+	}
+	if config.GlobalBurst <= 0 {
+		config.GlobalBurst = 20
+	}
+	if config.ResultWindow <= 0 {
+		config.ResultWindow = 20
+	}
+	if config.DisableThreshold <= 0 {
+		config.DisableThreshold = 0.5
+	}
+	if config.DisableConsecutiveCycles <= 0 {
+		config.DisableConsecutiveCycles = 3
+	}
+	if config.ReEnableStreak <= 0 {
+		config.ReEnableStreak = 3
+	}
+	if config.CacheTTL == "" {
+		config.CacheTTL = "2m"
+	}
+	if config.ChannelLockTTL == "" {
+		config.ChannelLockTTL = "5m"
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// channelLimiters 按渠道ID缓存限流器，避免每次探测都重新创建
+var (
+	channelLimiters   = make(map[int]*rate.Limiter)
+	channelLimitersMu sync.Mutex
+	globalLimiter     *rate.Limiter
+)
+
+func initLimiters(cfg *Config) {
+	globalLimiter = rate.NewLimiter(rate.Limit(cfg.GlobalRPS), cfg.GlobalBurst)
+}
+
+// getChannelLimiter 获取（或创建）某个渠道专用的限流器
+func getChannelLimiter(channelID int, cfg *Config) *rate.Limiter {
+	channelLimitersMu.Lock()
+	defer channelLimitersMu.Unlock()
+
+	if limiter, ok := channelLimiters[channelID]; ok {
+		return limiter
+	}
+	limiter := rate.NewLimiter(rate.Limit(cfg.ChannelRPS), cfg.ChannelBurst)
+	channelLimiters[channelID] = limiter
+	return limiter
+}
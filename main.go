@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
-	"fmt"
+	"errors"
+	"github.com/DullJZ/ChannelMonitor/api"
+	"github.com/DullJZ/ChannelMonitor/prober"
 	_ "github.com/go-sql-driver/mysql"
-	"io/ioutil"
 	"log"
-	"net/http"
+	"os/signal"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 )
 
@@ -18,6 +21,9 @@ type Channel struct {
 	BaseURL string
 	Key     string
 	Status  int
+	// Type 标识上游 API 风格（openai/anthropic/gemini/ollama/embeddings），
+	// 始终由 BaseURL 推断，详见 inferProviderType
+	Type string
 }
 
 var (
@@ -48,8 +54,9 @@ func fetchChannels() ([]Channel, error) {
 		if err := rows.Scan(&c.ID, &c.Name, &c.BaseURL, &c.Key, &c.Status); err != nil {
 			return nil, err
 		}
+		c.Type = inferProviderType(c.BaseURL)
 		// 检查是否在排除列表中
-		if contains(config.ExcludeChannel, c.ID) {
+		if isExcluded(c.ID) {
 			log.Printf("渠道 %s(ID:%d) 在排除列表中，跳过\n", c.Name, c.ID)
 			continue
 		}
@@ -69,93 +76,97 @@ func contains(slice []int, item int) bool {
 	return false
 }
 
-func testModels(channel Channel) ([]string, error) {
-	var availableModels []string
-	// 从/v1/models接口获取模型列表
-	req, err := http.NewRequest("GET", channel.BaseURL+"/v1/models", nil)
-	if err != nil {
-		return nil, fmt.Errorf("创建请求失败：%v", err)
-	}
-	req.Header.Set("Authorization", "Bearer "+channel.Key)
+// excludeMu 保护运行时通过管理接口对排除列表的增删，避免与检测周期中的读取竞争
+var excludeMu sync.RWMutex
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	modelList := []string{}
-	if err != nil {
-		log.Println("获取模型列表失败：", err, "尝试自定义模型列表")
-		modelList = config.Models
-	} else {
-		defer resp.Body.Close()
-		body, _ := ioutil.ReadAll(resp.Body)
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("获取模型列表失败，状态码：%d，响应：%s", resp.StatusCode, string(body))
-		}
+func isExcluded(id int) bool {
+	excludeMu.RLock()
+	defer excludeMu.RUnlock()
+	return contains(config.ExcludeChannel, id)
+}
 
-		// 解析响应JSON
-		var response struct {
-			Data []struct {
-				ID string `json:"id"`
-			} `json:"data"`
-		}
+func setExcluded(id int, excluded bool) error {
+	excludeMu.Lock()
+	defer excludeMu.Unlock()
 
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, fmt.Errorf("解析模型列表失败：%v", err)
-		}
-		// 提取模型ID列表
-		for _, model := range response.Data {
-			modelList = append(modelList, model.ID)
+	if excluded {
+		if !contains(config.ExcludeChannel, id) {
+			config.ExcludeChannel = append(config.ExcludeChannel, id)
 		}
+		return nil
 	}
-	// 测试模型
-	for _, model := range modelList {
-		url := channel.BaseURL
-		if !strings.Contains(channel.BaseURL, "/v1/chat/completions") {
-			if !strings.HasSuffix(channel.BaseURL, "/chat") {
-				if !strings.HasSuffix(channel.BaseURL, "/v1") {
-					url += "/v1"
-				}
-				url += "/chat"
-			}
-			url += "/completions"
-		}
 
-		// 构造请求
-		reqBody := map[string]interface{}{
-			"model": model,
-			"messages": []map[string]string{
-				{"role": "user", "content": "Hello! Reply in short"},
-			},
+	filtered := config.ExcludeChannel[:0]
+	for _, v := range config.ExcludeChannel {
+		if v != id {
+			filtered = append(filtered, v)
 		}
-		jsonData, _ := json.Marshal(reqBody)
+	}
+	config.ExcludeChannel = filtered
+	return nil
+}
 
-		log.Printf("测试渠道 %s(ID:%d) 的模型 %s\n", channel.Name, channel.ID, model)
+// listModels 通过 fetchModels 获取渠道的模型列表（仅在连不上上游时回退到配置中的自定义模型列表）。
+// 若 Redis 缓存中存在 CacheTTL 内的结果则直接复用，避免短周期内重复请求上游。
+func listModels(ctx context.Context, channel Channel) ([]string, error) {
+	if models, hit := getCachedModels(ctx, channel.ID); hit {
+		return models, nil
+	}
 
-		req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonData)))
-		if err != nil {
-			log.Println("创建请求失败：", err)
-			continue
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+channel.Key)
+	models, err := fetchModels(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	cacheModels(ctx, channel.ID, models, cacheTTL)
+	return models, nil
+}
 
-		client := &http.Client{Timeout: 10 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			log.Printf("\033[31m请求失败：%v\033[0m\n", err)
-			continue
+// fetchModels 通过渠道对应的 Prober 适配器拉取模型列表，并按 Config.ModelFilters 过滤。
+// 只有完全连不上上游（ErrUnreachable）才回退到自定义模型列表；渠道已响应但状态码异常
+// 或响应体无法解析（例如 key 被吊销返回 401）是真实错误，原样返回，交由调用方跳过本轮、
+// 保留渠道上一次已知的模型列表，而不是把它们当成模型全部消失来处理。
+func fetchModels(ctx context.Context, channel Channel) ([]string, error) {
+	models, err := prober.For(channel.Type).ListModels(ctx, toProberChannel(channel))
+	if err != nil {
+		if errors.Is(err, prober.ErrUnreachable) {
+			log.Println("连接上游失败：", err, "尝试自定义模型列表")
+			return config.Models, nil
 		}
-		defer resp.Body.Close()
+		return nil, err
+	}
+	return filterModels(channel.Type, models), nil
+}
 
-		body, _ := ioutil.ReadAll(resp.Body)
-		if resp.StatusCode == http.StatusOK {
-			// 根据返回内容判断是否成功
-			availableModels = append(availableModels, model)
-			log.Printf("\033[32m渠道 %s(ID:%d) 的模型 %s 测试成功\033[0m\n", channel.Name, channel.ID, model)
-		} else {
-			log.Printf("\033[31m渠道 %s(ID:%d) 的模型 %s 测试失败，状态码：%d，响应：%s\033[0m\n", channel.Name, channel.ID, model, resp.StatusCode, string(body))
-		}
+// probeModel 通过渠道对应的 Prober 适配器探测单个模型，发起前会分别等待渠道级与全局限流器放行。
+// 若 Redis 缓存中存在 CacheTTL 内的探测结果则直接复用。
+func probeModel(ctx context.Context, channel Channel, model string) (bool, error) {
+	if ok, hit := getCachedProbe(ctx, channel.ID, model); hit {
+		return ok, nil
+	}
+
+	if err := getChannelLimiter(channel.ID, config).Wait(ctx); err != nil {
+		return false, err
 	}
-	return availableModels, nil
+	if err := globalLimiter.Wait(ctx); err != nil {
+		return false, err
+	}
+
+	log.Printf("测试渠道 %s(ID:%d) 的模型 %s\n", channel.Name, channel.ID, model)
+
+	ok, statusCode, latency, err := prober.For(channel.Type).Probe(ctx, toProberChannel(channel), model)
+
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+	api.RecordProbe(channel.ID, channel.Name, model, ok, latency)
+	recordResult(channel.ID, model, ok, statusCode, latency, errMsg)
+	cacheProbe(ctx, channel.ID, model, ok, cacheTTL)
+
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, nil
 }
 
 func updateModels(channelID int, models []string) error {
@@ -171,6 +182,9 @@ func main() {
 	if err != nil {
 		log.Fatal("加载配置失败：", err)
 	}
+	initLimiters(config)
+	initRedis(config)
+	initNotifier(config)
 
 	// 解析时间周期
 	duration, err := time.ParseDuration(config.TimePeriod)
@@ -184,6 +198,15 @@ func main() {
 	}
 	defer db.Close()
 
+	if err := initResultsTable(); err != nil {
+		log.Fatal("初始化探测历史表失败：", err)
+	}
+
+	startAPIServer(config)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	ticker := time.NewTicker(duration)
 	defer ticker.Stop()
 
@@ -192,25 +215,15 @@ func main() {
 		channels, err := fetchChannels()
 		if err != nil {
 			log.Printf("\033[31m获取渠道失败：%v\033[0m\n", err)
-			continue
+		} else {
+			runTestCycle(ctx, channels)
 		}
 
-		for _, channel := range channels {
-			log.Printf("开始测试渠道 %s(ID:%d) 的模型\n", channel.Name, channel.ID)
-			models, err := testModels(channel)
-			if err != nil {
-				log.Printf("\033[31m渠道 %s(ID:%d) 测试模型失败：%v\033[0m\n", channel.Name, channel.ID, err)
-				continue
-			}
-			err = updateModels(channel.ID, models)
-			if err != nil {
-				log.Printf("\033[31m更新渠道 %s(ID:%d) 的模型失败：%v\033[0m\n", channel.Name, channel.ID, err)
-			} else {
-				log.Printf("渠道 %s(ID:%d) 可用模型：%v\n", channel.Name, channel.ID, models)
-			}
+		select {
+		case <-ctx.Done():
+			log.Println("收到退出信号，等待在途请求结束后退出")
+			return
+		case <-ticker.C:
 		}
-
-		// 等待下一个周期
-		<-ticker.C
 	}
 }
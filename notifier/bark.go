@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// barkNotifier 通过 Bark 推送服务（https://bark.day.app）发送告警，url 应为形如
+// https://api.day.app/<key> 的推送地址
+type barkNotifier struct {
+	url string
+}
+
+func (b *barkNotifier) Name() string { return "bark:" + b.url }
+
+func (b *barkNotifier) Send(ctx context.Context, event Event) error {
+	title := string(event.Type)
+	body := renderMarkdown(event)
+
+	endpoint := strings.TrimRight(b.url, "/") + "/" + url.PathEscape(title) + "/" + url.PathEscape(body)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("bark 响应状态码：%d", resp.StatusCode)
+	}
+	return nil
+}
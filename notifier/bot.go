@@ -0,0 +1,86 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// botNotifier 适配飞书/钉钉/企微的 markdown 机器人webhook，三者请求体结构基本一致
+type botNotifier struct {
+	kind string
+	url  string
+}
+
+func (b *botNotifier) Name() string { return b.kind + ":" + b.url }
+
+func (b *botNotifier) Send(ctx context.Context, event Event) error {
+	text := renderMarkdown(event)
+
+	var payload map[string]interface{}
+	switch b.kind {
+	case "dingtalk":
+		payload = map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"title": string(event.Type),
+				"text":  text,
+			},
+		}
+	case "wecom":
+		payload = map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"content": text,
+			},
+		}
+	default: // feishu，post 消息支持富文本，text 消息不会渲染 markdown 语法
+		payload = map[string]interface{}{
+			"msg_type": "post",
+			"content": map[string]interface{}{
+				"post": map[string]interface{}{
+					"zh_cn": map[string]interface{}{
+						"title":   string(event.Type),
+						"content": [][]map[string]string{{{"tag": "text", "text": text}}},
+					},
+				},
+			},
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s 机器人响应状态码：%d", b.kind, resp.StatusCode)
+	}
+	return nil
+}
+
+func renderMarkdown(event Event) string {
+	switch event.Type {
+	case EventChannelDown:
+		return fmt.Sprintf("渠道 **%s**(ID:%d) 已失联，丢失模型：%v", event.ChannelName, event.ChannelID, event.ModelsLost)
+	case EventModelAdded:
+		return fmt.Sprintf("渠道 **%s**(ID:%d) 新增可用模型：%v", event.ChannelName, event.ChannelID, event.ModelsAdded)
+	case EventModelRemoved:
+		return fmt.Sprintf("渠道 **%s**(ID:%d) 丢失模型：%v", event.ChannelName, event.ChannelID, event.ModelsLost)
+	default:
+		return fmt.Sprintf("渠道 **%s**(ID:%d) 状态发生变化：%s", event.ChannelName, event.ChannelID, event.Type)
+	}
+}
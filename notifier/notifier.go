@@ -0,0 +1,127 @@
+// Package notifier 在渠道探测结果发生变化时对外发送告警，
+// 支持通用 webhook、飞书/钉钉/企微机器人、Bark 推送与 SMTP 邮件。
+package notifier
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+var severityRank = map[Severity]int{SeverityInfo: 0, SeverityWarning: 1, SeverityCritical: 2}
+
+type EventType string
+
+const (
+	EventChannelDown  EventType = "ChannelDown"
+	EventModelAdded   EventType = "ModelAdded"
+	EventModelRemoved EventType = "ModelRemoved"
+)
+
+// Event 描述一次需要告警的渠道状态变化
+type Event struct {
+	Type        EventType
+	ChannelID   int
+	ChannelName string
+	ModelsLost  []string
+	ModelsAdded []string
+	Severity    Severity
+	Timestamp   time.Time
+}
+
+// Notifier 是一种告警发送方式的抽象
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, event Event) error
+}
+
+// Config 对应 Config.Notifiers 中的一项，描述如何接入一个具体的通知渠道
+type Config struct {
+	Type        string   `json:"type"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	MinSeverity Severity `json:"min_severity"`
+}
+
+type entry struct {
+	notifier    Notifier
+	minSeverity Severity
+}
+
+// Manager 持有全部已配置的通知渠道，负责按最低严重度过滤并异步分发事件
+type Manager struct {
+	entries []entry
+}
+
+// NewManager 根据配置构建通知渠道集合，未知类型会被跳过并记录日志
+func NewManager(configs []Config) *Manager {
+	m := &Manager{}
+	for _, cfg := range configs {
+		n := build(cfg)
+		if n == nil {
+			log.Printf("\033[31m未知的通知渠道类型：%s\033[0m\n", cfg.Type)
+			continue
+		}
+		minSeverity := cfg.MinSeverity
+		if minSeverity == "" {
+			minSeverity = SeverityInfo
+		}
+		m.entries = append(m.entries, entry{notifier: n, minSeverity: minSeverity})
+	}
+	return m
+}
+
+func build(cfg Config) Notifier {
+	switch cfg.Type {
+	case "webhook":
+		return &webhookNotifier{url: cfg.URL, secret: cfg.Secret}
+	case "feishu", "dingtalk", "wecom":
+		return &botNotifier{kind: cfg.Type, url: cfg.URL}
+	case "bark":
+		return &barkNotifier{url: cfg.URL}
+	case "smtp", "email":
+		return &smtpNotifier{addr: cfg.URL, secret: cfg.Secret}
+	default:
+		return nil
+	}
+}
+
+// Notify 把事件异步分发给所有级别足够的通知渠道，单个渠道发送失败不会阻塞探测循环，
+// 也不会影响其他渠道的发送
+func (m *Manager) Notify(ctx context.Context, event Event) {
+	if m == nil {
+		return
+	}
+	for _, e := range m.entries {
+		if severityRank[event.Severity] < severityRank[e.minSeverity] {
+			continue
+		}
+		go sendWithRetry(ctx, e.notifier, event)
+	}
+}
+
+// sendWithRetry 以指数退避最多发送5次（首次发送加4次重试），失败只记录日志
+func sendWithRetry(ctx context.Context, n Notifier, event Event) {
+	backoff := time.Second
+	for attempt := 1; attempt <= 5; attempt++ {
+		if err := n.Send(ctx, event); err != nil {
+			log.Printf("\033[31m通知渠道 %s 发送失败（第%d次）：%v\033[0m\n", n.Name(), attempt, err)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			backoff *= 2
+			continue
+		}
+		return
+	}
+}
@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"net/url"
+	"strings"
+)
+
+// smtpNotifier 通过 SMTP 发送邮件告警。
+// addr (Config.URL) 形如 "smtp://host:port/?from=alerts@example.com&to=a@example.com,b@example.com"，
+// secret (Config.Secret) 形如 "username:password"，鉴权信息与收发地址分开配置，避免凭证出现在URL里。
+type smtpNotifier struct {
+	addr   string
+	secret string
+}
+
+func (s *smtpNotifier) Name() string { return "smtp:" + s.addr }
+
+func (s *smtpNotifier) Send(ctx context.Context, event Event) error {
+	u, err := url.Parse(s.addr)
+	if err != nil {
+		return fmt.Errorf("解析SMTP地址失败：%v", err)
+	}
+
+	username, password, _ := strings.Cut(s.secret, ":")
+	from := u.Query().Get("from")
+	toParam := u.Query().Get("to")
+	if toParam == "" {
+		return fmt.Errorf("SMTP通知缺少收件人（to参数）")
+	}
+	to := strings.Split(toParam, ",")
+
+	subject := string(event.Type)
+	body := renderMarkdown(event)
+	msg := []byte(fmt.Sprintf("To: %s\r\nSubject: %s\r\n\r\n%s\r\n", strings.Join(to, ","), subject, body))
+
+	auth := smtp.PlainAuth("", username, password, u.Hostname())
+	return smtp.SendMail(u.Host, auth, from, to, msg)
+}
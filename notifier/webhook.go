@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// webhookNotifier 向任意地址 POST 一段通用 JSON 负载
+type webhookNotifier struct {
+	url    string
+	secret string
+}
+
+func (w *webhookNotifier) Name() string { return "webhook:" + w.url }
+
+func (w *webhookNotifier) Send(ctx context.Context, event Event) error {
+	payload := map[string]interface{}{
+		"channel":      event.ChannelName,
+		"channel_id":   event.ChannelID,
+		"event":        event.Type,
+		"models_lost":  event.ModelsLost,
+		"models_added": event.ModelsAdded,
+		"timestamp":    event.Timestamp.Unix(),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		req.Header.Set("X-Webhook-Secret", w.secret)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook 响应状态码：%d", resp.StatusCode)
+	}
+	return nil
+}
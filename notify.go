@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/DullJZ/ChannelMonitor/notifier"
+)
+
+var notifyManager *notifier.Manager
+
+func initNotifier(cfg *Config) {
+	notifyManager = notifier.NewManager(cfg.Notifiers)
+}
+
+// previousModels 读取渠道在本次更新前已知的可用模型列表，用于和新结果做差异比较
+func previousModels(channelID int) []string {
+	var modelsStr string
+	row := db.QueryRow("SELECT models FROM channels WHERE id = ?", channelID)
+	if err := row.Scan(&modelsStr); err != nil {
+		log.Printf("\033[31m查询渠道 %d 历史模型失败：%v\033[0m\n", channelID, err)
+		return nil
+	}
+	if modelsStr == "" {
+		return nil
+	}
+	return strings.Split(modelsStr, ",")
+}
+
+// diffAndNotify 对比新旧可用模型列表，触发 ChannelDown / ModelAdded / ModelRemoved 通知
+func diffAndNotify(ctx context.Context, channel Channel, oldModels, newModels []string) {
+	oldSet := toSet(oldModels)
+	newSet := toSet(newModels)
+
+	var added, removed []string
+	for m := range newSet {
+		if !oldSet[m] {
+			added = append(added, m)
+		}
+	}
+	for m := range oldSet {
+		if !newSet[m] {
+			removed = append(removed, m)
+		}
+	}
+
+	now := time.Now()
+	if len(newModels) == 0 && len(oldModels) > 0 {
+		notifyManager.Notify(ctx, notifier.Event{
+			Type:        notifier.EventChannelDown,
+			ChannelID:   channel.ID,
+			ChannelName: channel.Name,
+			ModelsLost:  removed,
+			Severity:    notifier.SeverityCritical,
+			Timestamp:   now,
+		})
+		return
+	}
+
+	if len(added) > 0 {
+		notifyManager.Notify(ctx, notifier.Event{
+			Type:        notifier.EventModelAdded,
+			ChannelID:   channel.ID,
+			ChannelName: channel.Name,
+			ModelsAdded: added,
+			Severity:    notifier.SeverityInfo,
+			Timestamp:   now,
+		})
+	}
+	if len(removed) > 0 {
+		notifyManager.Notify(ctx, notifier.Event{
+			Type:        notifier.EventModelRemoved,
+			ChannelID:   channel.ID,
+			ChannelName: channel.Name,
+			ModelsLost:  removed,
+			Severity:    notifier.SeverityWarning,
+			Timestamp:   now,
+		})
+	}
+}
+
+func toSet(models []string) map[string]bool {
+	set := make(map[string]bool, len(models))
+	for _, m := range models {
+		set[m] = true
+	}
+	return set
+}
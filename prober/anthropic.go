@@ -0,0 +1,90 @@
+package prober
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// anthropicProber 适配 Anthropic Messages API（/v1/messages，x-api-key + anthropic-version 头）
+type anthropicProber struct{}
+
+const anthropicVersion = "2023-06-01"
+
+func (p *anthropicProber) Name() string { return "anthropic" }
+
+func (p *anthropicProber) ListModels(ctx context.Context, ch Channel) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimRight(ch.BaseURL, "/")+"/v1/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败：%v", err)
+	}
+	req.Header.Set("x-api-key", ch.Key)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	client := &http.Client{Timeout: httpTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w：%v", ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("获取模型列表失败，状态码：%d，响应：%s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("解析模型列表失败：%v", err)
+	}
+
+	models := make([]string, 0, len(response.Data))
+	for _, model := range response.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
+
+func (p *anthropicProber) Probe(ctx context.Context, ch Channel, model string) (bool, int, time.Duration, error) {
+	url := strings.TrimRight(ch.BaseURL, "/") + "/v1/messages"
+
+	reqBody := map[string]interface{}{
+		"model":      model,
+		"max_tokens": 16,
+		"messages": []map[string]string{
+			{"role": "user", "content": "Hello! Reply in short"},
+		},
+	}
+	jsonData, _ := json.Marshal(reqBody)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(jsonData)))
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("创建请求失败：%v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", ch.Key)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	client := &http.Client{Timeout: httpTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return false, 0, latency, fmt.Errorf("请求失败：%v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return false, resp.StatusCode, latency, fmt.Errorf("状态码：%d，响应：%s", resp.StatusCode, string(body))
+	}
+	return true, resp.StatusCode, latency, nil
+}
@@ -0,0 +1,53 @@
+package prober
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// embeddingsProber 适配仅提供 embedding 能力的渠道，模型列表复用 OpenAI 风格的 /v1/models，
+// 探测改为请求 /v1/embeddings 而非对话接口
+type embeddingsProber struct{}
+
+func (p *embeddingsProber) Name() string { return "embeddings" }
+
+func (p *embeddingsProber) ListModels(ctx context.Context, ch Channel) ([]string, error) {
+	return registry["openai"].ListModels(ctx, ch)
+}
+
+func (p *embeddingsProber) Probe(ctx context.Context, ch Channel, model string) (bool, int, time.Duration, error) {
+	url := strings.TrimRight(ch.BaseURL, "/") + "/v1/embeddings"
+
+	reqBody := map[string]interface{}{
+		"model": model,
+		"input": "Hello! Reply in short",
+	}
+	jsonData, _ := json.Marshal(reqBody)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(jsonData)))
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("创建请求失败：%v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+ch.Key)
+
+	client := &http.Client{Timeout: httpTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return false, 0, latency, fmt.Errorf("请求失败：%v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return false, resp.StatusCode, latency, fmt.Errorf("状态码：%d，响应：%s", resp.StatusCode, string(body))
+	}
+	return true, resp.StatusCode, latency, nil
+}
@@ -0,0 +1,83 @@
+package prober
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// geminiProber 适配 Google Gemini generateContent 接口（API key 以 ?key= 查询参数传递）
+type geminiProber struct{}
+
+func (p *geminiProber) Name() string { return "gemini" }
+
+func (p *geminiProber) ListModels(ctx context.Context, ch Channel) ([]string, error) {
+	url := strings.TrimRight(ch.BaseURL, "/") + "/v1beta/models?key=" + ch.Key
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败：%v", err)
+	}
+
+	client := &http.Client{Timeout: httpTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w：%v", ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("获取模型列表失败，状态码：%d，响应：%s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("解析模型列表失败：%v", err)
+	}
+
+	models := make([]string, 0, len(response.Models))
+	for _, model := range response.Models {
+		models = append(models, strings.TrimPrefix(model.Name, "models/"))
+	}
+	return models, nil
+}
+
+func (p *geminiProber) Probe(ctx context.Context, ch Channel, model string) (bool, int, time.Duration, error) {
+	url := fmt.Sprintf("%s/v1beta/models/%s:generateContent?key=%s", strings.TrimRight(ch.BaseURL, "/"), model, ch.Key)
+
+	reqBody := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{"parts": []map[string]string{{"text": "Hello! Reply in short"}}},
+		},
+	}
+	jsonData, _ := json.Marshal(reqBody)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(jsonData)))
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("创建请求失败：%v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: httpTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return false, 0, latency, fmt.Errorf("请求失败：%v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return false, resp.StatusCode, latency, fmt.Errorf("状态码：%d，响应：%s", resp.StatusCode, string(body))
+	}
+	return true, resp.StatusCode, latency, nil
+}
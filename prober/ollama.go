@@ -0,0 +1,84 @@
+package prober
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ollamaProber 适配 Ollama 的 /api/tags 与 /api/chat 接口
+type ollamaProber struct{}
+
+func (p *ollamaProber) Name() string { return "ollama" }
+
+func (p *ollamaProber) ListModels(ctx context.Context, ch Channel) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimRight(ch.BaseURL, "/")+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败：%v", err)
+	}
+
+	client := &http.Client{Timeout: httpTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w：%v", ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("获取模型列表失败，状态码：%d，响应：%s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("解析模型列表失败：%v", err)
+	}
+
+	models := make([]string, 0, len(response.Models))
+	for _, model := range response.Models {
+		models = append(models, model.Name)
+	}
+	return models, nil
+}
+
+func (p *ollamaProber) Probe(ctx context.Context, ch Channel, model string) (bool, int, time.Duration, error) {
+	url := strings.TrimRight(ch.BaseURL, "/") + "/api/chat"
+
+	reqBody := map[string]interface{}{
+		"model": model,
+		"messages": []map[string]string{
+			{"role": "user", "content": "Hello! Reply in short"},
+		},
+		"stream": false,
+	}
+	jsonData, _ := json.Marshal(reqBody)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(jsonData)))
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("创建请求失败：%v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: httpTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return false, 0, latency, fmt.Errorf("请求失败：%v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return false, resp.StatusCode, latency, fmt.Errorf("状态码：%d，响应：%s", resp.StatusCode, string(body))
+	}
+	return true, resp.StatusCode, latency, nil
+}
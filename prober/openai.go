@@ -0,0 +1,94 @@
+package prober
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// openAIProber 适配 OpenAI 风格的 /v1/models 与 /v1/chat/completions 接口
+type openAIProber struct{}
+
+func (p *openAIProber) Name() string { return "openai" }
+
+func (p *openAIProber) ListModels(ctx context.Context, ch Channel) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", ch.BaseURL+"/v1/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败：%v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+ch.Key)
+
+	client := &http.Client{Timeout: httpTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w：%v", ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("获取模型列表失败，状态码：%d，响应：%s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("解析模型列表失败：%v", err)
+	}
+
+	models := make([]string, 0, len(response.Data))
+	for _, model := range response.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
+
+func (p *openAIProber) Probe(ctx context.Context, ch Channel, model string) (bool, int, time.Duration, error) {
+	url := ch.BaseURL
+	if !strings.Contains(ch.BaseURL, "/v1/chat/completions") {
+		if !strings.HasSuffix(ch.BaseURL, "/chat") {
+			if !strings.HasSuffix(ch.BaseURL, "/v1") {
+				url += "/v1"
+			}
+			url += "/chat"
+		}
+		url += "/completions"
+	}
+
+	reqBody := map[string]interface{}{
+		"model": model,
+		"messages": []map[string]string{
+			{"role": "user", "content": "Hello! Reply in short"},
+		},
+	}
+	jsonData, _ := json.Marshal(reqBody)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(jsonData)))
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("创建请求失败：%v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+ch.Key)
+
+	client := &http.Client{Timeout: httpTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return false, 0, latency, fmt.Errorf("请求失败：%v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return false, resp.StatusCode, latency, fmt.Errorf("状态码：%d，响应：%s", resp.StatusCode, string(body))
+	}
+	return true, resp.StatusCode, latency, nil
+}
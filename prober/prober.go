@@ -0,0 +1,59 @@
+// Package prober 定义了不同上游 API 风格的探测适配器，
+// 让 ChannelMonitor 不再局限于 OpenAI 风格的 /v1/chat/completions。
+package prober
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Channel 是探测一个渠道所需的最小信息集合，独立于 main 包的 Channel 类型，避免循环依赖
+type Channel struct {
+	ID      int
+	Name    string
+	BaseURL string
+	Key     string
+}
+
+// ErrUnreachable 标记“请求压根没发出去”（建连失败、DNS失败、超时等传输层错误），
+// 与渠道正常响应了非200状态码或返回了无法解析的内容这类“连上了但结果不对”的错误区分开，
+// 调用方据此决定要不要回退到配置里的自定义模型列表
+var ErrUnreachable = errors.New("无法连接到上游")
+
+// Prober 描述了一种上游 API 风格的探测方式
+type Prober interface {
+	// Name 返回适配器名称，与 Channel.Type 对应
+	Name() string
+	// ListModels 拉取渠道支持的模型列表。请求未能发出时返回的 error 会包装 ErrUnreachable，
+	// 状态码异常或响应体无法解析则返回普通 error。
+	ListModels(ctx context.Context, ch Channel) ([]string, error)
+	// Probe 对单个模型发起一次最小化请求，返回是否成功、HTTP状态码、耗时与错误信息。
+	// 请求未能发出（建连/超时等）时 statusCode 为 0。
+	Probe(ctx context.Context, ch Channel, model string) (ok bool, statusCode int, latency time.Duration, err error)
+}
+
+// 内置适配器按 Channel.Type 注册，OpenAI 适配器同时作为默认值
+var registry = map[string]Prober{}
+
+func register(p Prober) {
+	registry[p.Name()] = p
+}
+
+// For 按类型返回对应的 Prober，未知或空类型时回退到 OpenAI 适配器
+func For(channelType string) Prober {
+	if p, ok := registry[channelType]; ok {
+		return p
+	}
+	return registry["openai"]
+}
+
+func init() {
+	register(&openAIProber{})
+	register(&anthropicProber{})
+	register(&geminiProber{})
+	register(&ollamaProber{})
+	register(&embeddingsProber{})
+}
+
+var httpTimeout = 10 * time.Second
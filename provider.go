@@ -0,0 +1,64 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/DullJZ/ChannelMonitor/prober"
+)
+
+// inferProviderType 根据 BaseURL 猜测渠道的上游 API 风格，渠道的 Type 只由它决定
+func inferProviderType(baseURL string) string {
+	lower := strings.ToLower(baseURL)
+	switch {
+	case strings.Contains(lower, "anthropic"):
+		return "anthropic"
+	case strings.Contains(lower, "generativelanguage.googleapis.com") || strings.Contains(lower, "gemini"):
+		return "gemini"
+	case strings.Contains(lower, "11434") || strings.Contains(lower, "ollama"):
+		return "ollama"
+	case strings.Contains(lower, "embedding"):
+		return "embeddings"
+	default:
+		return "openai"
+	}
+}
+
+func toProberChannel(channel Channel) prober.Channel {
+	return prober.Channel{ID: channel.ID, Name: channel.Name, BaseURL: channel.BaseURL, Key: channel.Key}
+}
+
+var (
+	modelFilterMu    sync.Mutex
+	modelFilterCache = make(map[string]*regexp.Regexp)
+)
+
+// filterModels 按 Config.ModelFilters 中该渠道类型对应的正则过滤模型列表，未配置时原样返回
+func filterModels(providerType string, models []string) []string {
+	pattern, ok := config.ModelFilters[providerType]
+	if !ok || pattern == "" {
+		return models
+	}
+
+	modelFilterMu.Lock()
+	re, cached := modelFilterCache[providerType]
+	if !cached {
+		var err error
+		re, err = regexp.Compile(pattern)
+		if err != nil {
+			modelFilterMu.Unlock()
+			return models
+		}
+		modelFilterCache[providerType] = re
+	}
+	modelFilterMu.Unlock()
+
+	filtered := make([]string, 0, len(models))
+	for _, model := range models {
+		if re.MatchString(model) {
+			filtered = append(filtered, model)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseLockScript 只有当锁的值仍然等于持有者自己的token时才删除，
+// 避免锁因探测耗时超过 ttl 过期后被其他实例抢到，而旧持有者的 release 却把新持有者的锁误删
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+else
+	return 0
+end
+`)
+
+// redisClient 为 nil 时表示未启用 Redis 集成，所有相关函数都会直接退化为单实例行为
+var (
+	redisClient    *redis.Client
+	cacheTTL       time.Duration
+	channelLockTTL time.Duration
+)
+
+func initRedis(cfg *Config) {
+	cacheTTL, _ = time.ParseDuration(cfg.CacheTTL)
+	channelLockTTL, _ = time.ParseDuration(cfg.ChannelLockTTL)
+
+	if cfg.RedisAddr == "" {
+		return
+	}
+	redisClient = redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+}
+
+func channelLockKey(channelID int) string {
+	return fmt.Sprintf("cm:lock:channel:%d", channelID)
+}
+
+// acquireChannelLock 尝试为渠道获取一把分布式锁，避免多实例部署时重复探测同一渠道。
+// 未配置 Redis 时始终返回可获取成功，释放函数为空操作。
+func acquireChannelLock(ctx context.Context, channelID int, ttl time.Duration) (bool, func(), error) {
+	if redisClient == nil {
+		return true, func() {}, nil
+	}
+
+	key := channelLockKey(channelID)
+	token, err := newLockToken()
+	if err != nil {
+		return false, func() {}, err
+	}
+
+	ok, err := redisClient.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return false, func() {}, err
+	}
+	if !ok {
+		return false, func() {}, nil
+	}
+
+	release := func() {
+		// 用 Lua 脚本做比较后删除：只有锁仍然是自己持有的token时才删除，
+		// 防止锁过期被其他实例抢占后，自己再把新持有者的锁误删
+		if err := releaseLockScript.Run(context.Background(), redisClient, []string{key}, token).Err(); err != nil {
+			log.Printf("\033[31m释放渠道锁 %s 失败：%v\033[0m\n", key, err)
+		}
+	}
+	return true, release, nil
+}
+
+func newLockToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成锁token失败：%v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func modelsCacheKey(channelID int) string {
+	return fmt.Sprintf("cm:cache:models:%d", channelID)
+}
+
+func probeCacheKey(channelID int, model string) string {
+	return fmt.Sprintf("cm:cache:probe:%d:%s", channelID, model)
+}
+
+type cachedProbeResult struct {
+	OK bool `json:"ok"`
+}
+
+// getCachedModels 返回某个渠道在 CacheTTL 内已缓存的模型列表，未命中时返回 ok=false
+func getCachedModels(ctx context.Context, channelID int) (models []string, ok bool) {
+	if redisClient == nil {
+		return nil, false
+	}
+	data, err := redisClient.Get(ctx, modelsCacheKey(channelID)).Result()
+	if err != nil {
+		return nil, false
+	}
+	if err := json.Unmarshal([]byte(data), &models); err != nil {
+		return nil, false
+	}
+	return models, true
+}
+
+func cacheModels(ctx context.Context, channelID int, models []string, ttl time.Duration) {
+	if redisClient == nil {
+		return
+	}
+	data, err := json.Marshal(models)
+	if err != nil {
+		return
+	}
+	if err := redisClient.Set(ctx, modelsCacheKey(channelID), data, ttl).Err(); err != nil {
+		log.Printf("\033[31m缓存渠道 %d 的模型列表失败：%v\033[0m\n", channelID, err)
+	}
+}
+
+// getCachedProbe 返回某个 (渠道, 模型) 在 CacheTTL 内已缓存的探测结果，未命中时返回 ok=false
+func getCachedProbe(ctx context.Context, channelID int, model string) (result bool, hit bool) {
+	if redisClient == nil {
+		return false, false
+	}
+	data, err := redisClient.Get(ctx, probeCacheKey(channelID, model)).Result()
+	if err != nil {
+		return false, false
+	}
+	var cached cachedProbeResult
+	if err := json.Unmarshal([]byte(data), &cached); err != nil {
+		return false, false
+	}
+	return cached.OK, true
+}
+
+func cacheProbe(ctx context.Context, channelID int, model string, ok bool, ttl time.Duration) {
+	if redisClient == nil {
+		return
+	}
+	data, err := json.Marshal(cachedProbeResult{OK: ok})
+	if err != nil {
+		return
+	}
+	if err := redisClient.Set(ctx, probeCacheKey(channelID, model), data, ttl).Err(); err != nil {
+		log.Printf("\033[31m缓存渠道 %d 模型 %s 的探测结果失败：%v\033[0m\n", channelID, model, err)
+	}
+}
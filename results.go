@@ -0,0 +1,133 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// 渠道状态约定：1 正常，2 人工禁用，3 因探测失败被自动禁用
+const (
+	channelStatusEnabled       = 1
+	channelStatusManualDisable = 2
+	channelStatusAutoDisable   = 3
+)
+
+const createResultsTableSQL = `
+CREATE TABLE IF NOT EXISTS channel_model_results (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	channel_id INT NOT NULL,
+	model VARCHAR(255) NOT NULL,
+	tested_at DATETIME NOT NULL,
+	ok TINYINT(1) NOT NULL,
+	status_code INT NOT NULL,
+	latency_ms BIGINT NOT NULL,
+	error TEXT,
+	INDEX idx_channel_tested_at (channel_id, tested_at)
+)`
+
+func initResultsTable() error {
+	_, err := db.Exec(createResultsTableSQL)
+	return err
+}
+
+// recordResult 写入一条探测结果，供成功率评分与 /channels/:id/history 使用
+func recordResult(channelID int, model string, ok bool, statusCode int, latency time.Duration, errMsg string) {
+	_, err := db.Exec(
+		"INSERT INTO channel_model_results (channel_id, model, tested_at, ok, status_code, latency_ms, error) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		channelID, model, time.Now(), ok, statusCode, latency.Milliseconds(), errMsg,
+	)
+	if err != nil {
+		log.Printf("\033[31m写入探测历史失败：%v\033[0m\n", err)
+	}
+}
+
+// channelSuccessRate 统计某个渠道最近 window 次探测（跨模型）的成功率
+func channelSuccessRate(channelID int, window int) (rate float64, total int, err error) {
+	rows, err := db.Query(
+		"SELECT ok FROM channel_model_results WHERE channel_id = ? ORDER BY tested_at DESC LIMIT ?",
+		channelID, window,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	var success int
+	for rows.Next() {
+		var ok bool
+		if err := rows.Scan(&ok); err != nil {
+			return 0, 0, err
+		}
+		total++
+		if ok {
+			success++
+		}
+	}
+	if total == 0 {
+		return 1, 0, nil
+	}
+	return float64(success) / float64(total), total, nil
+}
+
+func setChannelStatus(channelID int, status int) error {
+	_, err := db.Exec("UPDATE channels SET status = ? WHERE id = ?", status, channelID)
+	return err
+}
+
+// channelHealth 记录某个渠道连续低于/高于成功率阈值的周期数，用于判断是否需要自动禁用/重新启用
+type channelHealth struct {
+	lowStreak int
+	okStreak  int
+}
+
+var (
+	healthMu    sync.Mutex
+	healthState = make(map[int]*channelHealth)
+)
+
+// evaluateChannelHealth 依据最近成功率决定是否需要自动禁用/重新启用渠道，每个检测周期结束后调用一次
+func evaluateChannelHealth(channel Channel) {
+	rate, total, err := channelSuccessRate(channel.ID, config.ResultWindow)
+	if err != nil {
+		log.Printf("\033[31m计算渠道 %s(ID:%d) 成功率失败：%v\033[0m\n", channel.Name, channel.ID, err)
+		return
+	}
+	if total == 0 {
+		return
+	}
+	log.Printf("渠道 %s(ID:%d) 最近 %d 次探测成功率 %.2f%%\n", channel.Name, channel.ID, total, rate*100)
+
+	healthMu.Lock()
+	state, ok := healthState[channel.ID]
+	if !ok {
+		state = &channelHealth{}
+		healthState[channel.ID] = state
+	}
+	if rate < config.DisableThreshold {
+		state.lowStreak++
+		state.okStreak = 0
+	} else {
+		state.okStreak++
+		state.lowStreak = 0
+	}
+	lowStreak, okStreak := state.lowStreak, state.okStreak
+	healthMu.Unlock()
+
+	switch {
+	case channel.Status == channelStatusEnabled && lowStreak >= config.DisableConsecutiveCycles:
+		if err := setChannelStatus(channel.ID, channelStatusAutoDisable); err != nil {
+			log.Printf("\033[31m自动禁用渠道 %s(ID:%d) 失败：%v\033[0m\n", channel.Name, channel.ID, err)
+		} else {
+			log.Printf("\033[31m渠道 %s(ID:%d) 连续 %d 个周期成功率低于 %.0f%%，已自动禁用\033[0m\n",
+				channel.Name, channel.ID, lowStreak, config.DisableThreshold*100)
+		}
+	case channel.Status == channelStatusAutoDisable && okStreak >= config.ReEnableStreak:
+		if err := setChannelStatus(channel.ID, channelStatusEnabled); err != nil {
+			log.Printf("\033[31m重新启用渠道 %s(ID:%d) 失败：%v\033[0m\n", channel.Name, channel.ID, err)
+		} else {
+			log.Printf("\033[32m渠道 %s(ID:%d) 连续 %d 个周期成功率不低于 %.0f%%，已自动重新启用\033[0m\n",
+					channel.Name, channel.ID, okStreak, config.DisableThreshold*100)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// job 代表一次 (渠道, 模型) 的探测任务
+type job struct {
+	channel Channel
+	model   string
+}
+
+// jobResult 是某个探测任务完成后的结果
+type jobResult struct {
+	channel Channel
+	model   string
+	ok      bool
+	err     error
+}
+
+// runTestCycle 并发探测所有渠道的所有模型：先为每个渠道拉取模型列表，
+// 再把 (渠道, 模型) 任务派发给固定数量的worker，最后按渠道汇总结果并
+// 调用一次 updateModels。ctx 被取消时会尽快停止派发新任务并等待在途请求结束。
+func runTestCycle(ctx context.Context, channels []Channel) {
+	jobs := make(chan job, config.Concurrency*2)
+	results := make(chan jobResult, config.Concurrency*2)
+
+	// 每个渠道允许同时在途的探测数，避免单个渠道的并发超出 PerChannelConcurrency
+	channelSlots := make(map[int]chan struct{})
+	expected := make(map[int]int)
+	releaseLock := make(map[int]func())
+	var metaMu sync.Mutex
+
+	var workersWg sync.WaitGroup
+	for i := 0; i < config.Concurrency; i++ {
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			for j := range jobs {
+				metaMu.Lock()
+				slot := channelSlots[j.channel.ID]
+				metaMu.Unlock()
+
+				select {
+				case slot <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				ok, err := probeModel(ctx, j.channel, j.model)
+				<-slot
+
+				select {
+				case results <- jobResult{channel: j.channel, model: j.model, ok: ok, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, channel := range channels {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			// 多实例部署时，先尝试获取该渠道的分布式锁，持有者之外的实例直接跳过这个渠道
+			acquired, release, err := acquireChannelLock(ctx, channel.ID, channelLockTTL)
+			if err != nil {
+				log.Printf("\033[31m获取渠道 %s(ID:%d) 的分布式锁失败：%v\033[0m\n", channel.Name, channel.ID, err)
+				continue
+			}
+			if !acquired {
+				log.Printf("渠道 %s(ID:%d) 的锁被其他实例持有，跳过\n", channel.Name, channel.ID)
+				continue
+			}
+
+			models, err := listModels(ctx, channel)
+			if err != nil {
+				log.Printf("\033[31m渠道 %s(ID:%d) 获取模型列表失败：%v\033[0m\n", channel.Name, channel.ID, err)
+				release()
+				continue
+			}
+
+			// 模型列表为空（例如渠道失联且未配置自定义模型列表，或过滤后没有剩余模型）时不会产生任何 job，
+			// 因此不会经过下面的结果收集流程，
+			// 这里直接按空模型列表收尾，确保 updateModels/健康评估/通知依旧会触发一次
+			if len(models) == 0 {
+				finishChannel(ctx, channel, nil)
+				release()
+				continue
+			}
+
+			metaMu.Lock()
+			expected[channel.ID] = len(models)
+			channelSlots[channel.ID] = make(chan struct{}, config.PerChannelConcurrency)
+			releaseLock[channel.ID] = release
+			metaMu.Unlock()
+
+			for _, model := range models {
+				select {
+				case jobs <- job{channel: channel, model: model}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	go func() {
+		workersWg.Wait()
+		close(results)
+	}()
+
+	collected := make(map[int][]string)
+	count := make(map[int]int)
+
+	for res := range results {
+		if res.err != nil {
+			log.Printf("\033[31m渠道 %s(ID:%d) 的模型 %s 测试失败：%v\033[0m\n", res.channel.Name, res.channel.ID, res.model, res.err)
+		} else if res.ok {
+			collected[res.channel.ID] = append(collected[res.channel.ID], res.model)
+			log.Printf("\033[32m渠道 %s(ID:%d) 的模型 %s 测试成功\033[0m\n", res.channel.Name, res.channel.ID, res.model)
+		}
+
+		count[res.channel.ID]++
+		metaMu.Lock()
+		total, ok := expected[res.channel.ID]
+		metaMu.Unlock()
+		if ok && count[res.channel.ID] == total {
+			channel := res.channel
+			finishChannel(ctx, channel, collected[channel.ID])
+
+			metaMu.Lock()
+			release := releaseLock[channel.ID]
+			metaMu.Unlock()
+			if release != nil {
+				release()
+			}
+		}
+	}
+}
+
+// finishChannel 收尾一个渠道本周期的探测：写入可用模型、对比新旧模型触发通知、评估健康状况。
+// models 为空既可能是"完全失联"，也可能是真的没有任何可用模型，两种情况都需要走完整流程。
+func finishChannel(ctx context.Context, channel Channel, models []string) {
+	previous := previousModels(channel.ID)
+	if err := updateModels(channel.ID, models); err != nil {
+		log.Printf("\033[31m更新渠道 %s(ID:%d) 的模型失败：%v\033[0m\n", channel.Name, channel.ID, err)
+		return
+	}
+	log.Printf("渠道 %s(ID:%d) 可用模型：%v\n", channel.Name, channel.ID, models)
+	diffAndNotify(ctx, channel, previous, models)
+	evaluateChannelHealth(channel)
+}